@@ -0,0 +1,91 @@
+package dm
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPDoer is satisfied by *http.Client and lets callers plug in an instrumented, rate-limited
+// or mocked client, via UploadOptions.HTTPClient or DownloadOptions.HTTPClient, instead of the
+// bare http.Client{} each function used to instantiate per call. ListObjects,
+// ListObjectsWithContext and AbortUpload take no HTTPDoer and always use defaultHTTPClient.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// defaultHTTPClient is used whenever a caller doesn't supply its own HTTPDoer via
+// UploadOptions.HTTPClient or DownloadOptions.HTTPClient. Unlike the zero-value http.Client{}
+// this package used to create per-request, it sets a timeout and reuses connections across
+// calls.
+var defaultHTTPClient HTTPDoer = &http.Client{
+	Timeout: 5 * time.Minute,
+	Transport: &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+// httpDoerOrDefault returns doer, falling back to defaultHTTPClient when doer is nil.
+func httpDoerOrDefault(doer HTTPDoer) HTTPDoer {
+	if doer == nil {
+		return defaultHTTPClient
+	}
+	return doer
+}
+
+// APIError is returned by every Forge OSS request in this package that receives a non-2xx
+// response, replacing the plain "[code] body" error strings this package used to return, so
+// callers can branch on the failure programmatically instead of parsing an error message.
+type APIError struct {
+	StatusCode int
+	RequestID  string
+	Code       string
+	Message    string
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("[%d] %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("[%d] %s", e.StatusCode, string(e.Body))
+}
+
+// Is reports two *APIError equal, for errors.Is, when they share a status code - so callers can
+// write errors.Is(err, &APIError{StatusCode: http.StatusForbidden}) without needing the exact
+// instance that was returned.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	return e.StatusCode == t.StatusCode
+}
+
+// IsRetryable reports whether the request that produced e is worth retrying: 100-199, 429 or
+// 500-599, the same classification Autodesk recommends for direct-to-S3 part uploads.
+func (e *APIError) IsRetryable() bool {
+	return (e.StatusCode >= 100 && e.StatusCode <= 199) ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		(e.StatusCode >= 500 && e.StatusCode <= 599)
+}
+
+// IsExpiredURL reports whether e represents a 403, which for a signed S3 URL means it has
+// expired and a fresh one should be requested rather than retrying in place.
+func (e *APIError) IsExpiredURL() bool {
+	return e.StatusCode == http.StatusForbidden
+}
+
+// newAPIError builds an APIError from an *http.Response whose body has not yet been read.
+func newAPIError(response *http.Response) *APIError {
+	body, _ := io.ReadAll(response.Body)
+	return &APIError{
+		StatusCode: response.StatusCode,
+		RequestID:  response.Header.Get("x-ads-request-id"),
+		Body:       body,
+		Message:    string(body),
+	}
+}
@@ -0,0 +1,364 @@
+package dm
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DownloadOptions configures how DownloadObjectTo splits, parallelizes and retries the ranged
+// GETs used to download an object.
+type DownloadOptions struct {
+	// Context, if set, is attached to every ranged GET so the caller can cancel or time out the
+	// whole download; the first non-retriable failure also cancels every in-flight and queued
+	// range. Defaults to context.Background().
+	Context context.Context
+	// PartSize is the size, in bytes, of each downloaded range. Defaults to defaultSize (100MB).
+	PartSize int64
+	// Concurrency is the number of ranges downloaded in parallel. Defaults to 1 (serial).
+	Concurrency int
+	// MaxRetries is the number of attempts made per range before giving up. Defaults to 3.
+	MaxRetries int
+	// RetryBackoff is the base delay used for exponential backoff between retries; the actual
+	// delay is RetryBackoff*2^attempt, capped at maxBackoff, plus jitter. Defaults to 1 second.
+	RetryBackoff time.Duration
+	// HTTPClient is used to issue every HTTP request of the download. Defaults to a shared
+	// http.Client with sane timeouts and connection pooling; set it to plug in an instrumented
+	// or mocked client.
+	HTTPClient HTTPDoer
+}
+
+// DefaultDownloadOptions returns the DownloadOptions used by DownloadObject.
+func DefaultDownloadOptions() DownloadOptions {
+	return DownloadOptions{
+		Context:      context.Background(),
+		PartSize:     defaultSize,
+		Concurrency:  1,
+		MaxRetries:   3,
+		RetryBackoff: time.Second,
+	}
+}
+
+// downloadRange is a single byte range to fetch, identified by its 0-based index so completed
+// ranges can be fed to the checksum in order regardless of which worker finishes first.
+type downloadRange struct {
+	index      int
+	start, end int64 // inclusive, as in the Range header
+}
+
+// downloadSession holds the signed S3 URL shared by every range worker of one DownloadObjectTo
+// call, so that any worker seeing a 403 can refresh it for the others.
+type downloadSession struct {
+	mu      sync.RWMutex
+	url     string
+	refresh func() (signedDownloadUrl, error)
+}
+
+func (s *downloadSession) currentURL() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.url
+}
+
+func (s *downloadSession) refreshURL() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fresh, err := s.refresh()
+	if err != nil {
+		return "", err
+	}
+	s.url = fresh.Url
+	return s.url, nil
+}
+
+// rangeResult is a completed range, still tagged with its index so the combiner can feed it to
+// the checksum in order. At most `concurrency` of these are ever outstanding at once; see tokens
+// in DownloadObjectTo.
+type rangeResult struct {
+	index int
+	data  []byte
+}
+
+// DownloadObjectTo downloads the object to w using HTTP Range GETs against the signed S3 URL
+// returned by getSignedDownloadUrl (S3 supports ranged GETs on presigned URLs). The object is
+// split into ceil(size/opts.PartSize) ranges, fetched by a worker pool bounded by
+// opts.Concurrency, and each is written at its offset via w.WriteAt. The aggregate SHA-1 is
+// computed in range order as ranges are written, with dispatch of new ranges gated so that at
+// most opts.Concurrency ranges can ever be downloaded-but-not-yet-hashed at once; this keeps
+// memory usage around opts.Concurrency*opts.PartSize even if the range containing the start of
+// the object happens to be the slowest to complete, regardless of the object's total size. The
+// aggregate SHA-1 is verified against the signed URL's reported checksum before returning. It
+// returns the number of bytes downloaded.
+func (a *OssAPI) DownloadObjectTo(bucketKey, objectName string, w io.WriterAt, opts DownloadOptions) (n int64, err error) {
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = defaultSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	maxRetries := opts.MaxRetries
+	if maxRetries < 1 {
+		maxRetries = 3
+	}
+	backoff := opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	doer := httpDoerOrDefault(opts.HTTPClient)
+
+	bearer, err := a.Authenticator.GetToken("data:read")
+	if err != nil {
+		return
+	}
+
+	downloadUrl, err := getSignedDownloadUrl(ctx, doer, a.BaseUrl(), bucketKey, objectName, bearer.AccessToken)
+	if err != nil {
+		return
+	}
+
+	size := int64(downloadUrl.Size)
+	if size == 0 {
+		return 0, nil
+	}
+
+	numRanges := int((size + partSize - 1) / partSize)
+	ranges := make([]downloadRange, numRanges)
+	for i := 0; i < numRanges; i++ {
+		start := int64(i) * partSize
+		end := start + partSize - 1
+		if end >= size {
+			end = size - 1
+		}
+		ranges[i] = downloadRange{index: i, start: start, end: end}
+	}
+
+	session := &downloadSession{url: downloadUrl.Url, refresh: func() (signedDownloadUrl, error) {
+		bearer, terr := a.Authenticator.GetToken("data:read")
+		if terr != nil {
+			return signedDownloadUrl{}, terr
+		}
+		return getSignedDownloadUrl(ctx, doer, a.BaseUrl(), bucketKey, objectName, bearer.AccessToken)
+	}}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rangesCh := make(chan downloadRange, concurrency)
+	results := make(chan rangeResult, concurrency)
+	errs := make(chan error, 1)
+	var wg sync.WaitGroup
+
+	// tokens bounds how many ranges may be in flight - downloaded, or downloaded and waiting in
+	// the combiner's pending map for an earlier range to finish - at any one time. Without this,
+	// a slow low-index range would let every later range pile up in pending uncapped, buffering
+	// up to the whole object in memory. One token is returned each time the combiner advances
+	// past a range, so the bound holds regardless of object size.
+	tokens := make(chan struct{}, concurrency)
+	for i := 0; i < concurrency; i++ {
+		tokens <- struct{}{}
+	}
+
+	for wk := 0; wk < concurrency; wk++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range rangesCh {
+				data, derr := downloadRangeWithRetry(ctx, doer, session, r, maxRetries, backoff)
+				if derr != nil {
+					select {
+					case errs <- derr:
+						cancel()
+					default:
+					}
+					return
+				}
+				if _, werr := w.WriteAt(data, r.start); werr != nil {
+					select {
+					case errs <- werr:
+						cancel()
+					default:
+					}
+					return
+				}
+				select {
+				case results <- rangeResult{index: r.index, data: data}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	hasher := sha1.New()
+	combinerErr := make(chan error, 1)
+	go func() {
+		pending := map[int][]byte{}
+		next := 0
+		for next < numRanges {
+			select {
+			case res, ok := <-results:
+				if !ok {
+					combinerErr <- fmt.Errorf("download incomplete: missing range %v", next)
+					return
+				}
+				pending[res.index] = res.data
+				for {
+					data, ok := pending[next]
+					if !ok {
+						break
+					}
+					hasher.Write(data)
+					delete(pending, next)
+					next++
+					select {
+					case tokens <- struct{}{}:
+					case <-ctx.Done():
+					}
+				}
+			case <-ctx.Done():
+				combinerErr <- ctx.Err()
+				return
+			}
+		}
+		combinerErr <- nil
+	}()
+
+sendLoop:
+	for _, r := range ranges {
+		select {
+		case <-tokens:
+		case <-ctx.Done():
+			break sendLoop
+		}
+		select {
+		case rangesCh <- r:
+		case <-ctx.Done():
+			break sendLoop
+		}
+	}
+	close(rangesCh)
+	wg.Wait()
+	close(results)
+	hashErr := <-combinerErr
+
+	select {
+	case err = <-errs:
+		return 0, err
+	default:
+	}
+	if hashErr != nil {
+		return 0, hashErr
+	}
+
+	if downloadUrl.Sha1 != "" {
+		if got := hex.EncodeToString(hasher.Sum(nil)); got != downloadUrl.Sha1 {
+			return 0, fmt.Errorf("downloaded object failed SHA-1 verification: expected %v, got %v", downloadUrl.Sha1, got)
+		}
+	}
+
+	return size, nil
+}
+
+// downloadRangeWithRetry fetches a single range, retrying with exponential backoff and jitter
+// on retriable failures (100-199, 429, 500-599) and refreshing the shared signed URL on a 403.
+func downloadRangeWithRetry(ctx context.Context, doer HTTPDoer, session *downloadSession, r downloadRange, maxRetries int, backoff time.Duration) ([]byte, error) {
+
+	var lastErr error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		data, err := getRangeWithContext(ctx, doer, session.currentURL(), r.start, r.end)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			if apiErr.IsExpiredURL() {
+				if _, rerr := session.refreshURL(); rerr != nil {
+					return nil, fmt.Errorf("error refreshing expired signed download URL:\n%w", rerr)
+				}
+				continue
+			}
+			if !apiErr.IsRetryable() {
+				return nil, lastErr
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoffWithJitter(backoff, attempt)):
+		}
+	}
+
+	return nil, fmt.Errorf("range %v-%v failed after %v attempts:\n%w", r.start, r.end, maxRetries, lastErr)
+}
+
+// getRangeWithContext issues a single ranged GET, returning an *APIError on a non-2xx response
+// so callers can decide whether to retry or refresh the signed URL.
+func getRangeWithContext(ctx context.Context, doer HTTPDoer, url string, start, end int64) (data []byte, err error) {
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	response, err := doer.Do(req)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusPartialContent && response.StatusCode != http.StatusOK {
+		err = newAPIError(response)
+		return
+	}
+
+	data, err = io.ReadAll(response.Body)
+
+	return
+}
+
+// memoryWriterAt is an io.WriterAt backed by an in-memory byte slice that grows as needed; it
+// is the WriterAt DownloadObject hands to DownloadObjectTo so that callers who just want
+// []byte don't need to manage a temporary file.
+type memoryWriterAt struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (m *memoryWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	end := off + int64(len(p))
+	if end > int64(len(m.data)) {
+		grown := make([]byte, end)
+		copy(grown, m.data)
+		m.data = grown
+	}
+	copy(m.data[off:end], p)
+
+	return len(p), nil
+}
@@ -0,0 +1,114 @@
+package dm
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDownloadObjectTo_ParallelRanges downloads an object split into several ranges with
+// concurrency > 1 and checks the reassembled bytes and aggregate SHA-1 match, and that ranges
+// were actually fetched in parallel rather than serially.
+func TestDownloadObjectTo_ParallelRanges(t *testing.T) {
+	data := make([]byte, 95) // not a multiple of partSize, to exercise a short last range
+	for i := range data {
+		data[i] = byte(i)
+	}
+	sum := sha1.Sum(data)
+	wantSha1 := hex.EncodeToString(sum[:])
+
+	var inFlight, maxInFlight int32
+
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/buckets/bucket/objects/obj/signeds3download", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, signedDownloadUrl{Status: "complete", Url: srv.URL + "/range", Size: len(data), Sha1: wantSha1})
+	})
+	mux.HandleFunc("/range", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			cur := atomic.LoadInt32(&maxInFlight)
+			if n <= cur || atomic.CompareAndSwapInt32(&maxInFlight, cur, n) {
+				break
+			}
+		}
+		// give the other worker goroutines a chance to dispatch their ranges before this one
+		// returns, so maxInFlight reflects real overlap instead of a race against how fast the
+		// in-memory httptest server happens to respond.
+		time.Sleep(20 * time.Millisecond)
+
+		var start, end int64
+		fmt.Sscanf(strings.TrimPrefix(r.Header.Get("Range"), "bytes="), "%d-%d", &start, &end)
+		if end >= int64(len(data)) {
+			end = int64(len(data)) - 1
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data[start : end+1])
+	})
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	a := &OssAPI{BucketAPI: testBucketAPI(srv)}
+
+	buf := &memoryWriterAt{}
+	opts := DownloadOptions{PartSize: 20, Concurrency: 4, MaxRetries: 3, RetryBackoff: time.Millisecond}
+	n, err := a.DownloadObjectTo("bucket", "obj", buf, opts)
+	if err != nil {
+		t.Fatalf("DownloadObjectTo: %v", err)
+	}
+	if n != int64(len(data)) {
+		t.Errorf("n = %v, want %v", n, len(data))
+	}
+	if string(buf.data) != string(data) {
+		t.Errorf("downloaded data does not match the source")
+	}
+	if atomic.LoadInt32(&maxInFlight) < 2 {
+		t.Errorf("ranges were not fetched in parallel (max in flight = %v)", maxInFlight)
+	}
+}
+
+// TestDownloadObjectTo_RefreshesExpiredURL checks that a 403 on a ranged GET triggers a fresh
+// call to signeds3download rather than failing outright.
+func TestDownloadObjectTo_RefreshesExpiredURL(t *testing.T) {
+	data := []byte("hello world")
+	sum := sha1.Sum(data)
+	wantSha1 := hex.EncodeToString(sum[:])
+
+	var signedURLRequests int32
+	var rangeCalls int32
+
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/buckets/bucket/objects/obj/signeds3download", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&signedURLRequests, 1)
+		writeJSON(t, w, signedDownloadUrl{Status: "complete", Url: srv.URL + "/range", Size: len(data), Sha1: wantSha1})
+	})
+	mux.HandleFunc("/range", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&rangeCalls, 1) == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data)
+	})
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	a := &OssAPI{BucketAPI: testBucketAPI(srv)}
+
+	buf := &memoryWriterAt{}
+	opts := DownloadOptions{PartSize: int64(len(data)), Concurrency: 1, MaxRetries: 3, RetryBackoff: time.Millisecond}
+	if _, err := a.DownloadObjectTo("bucket", "obj", buf, opts); err != nil {
+		t.Fatalf("DownloadObjectTo: %v", err)
+	}
+	if got := atomic.LoadInt32(&signedURLRequests); got < 2 {
+		t.Errorf("signed download URL was requested %v times, want at least 2 (initial + refresh after the 403)", got)
+	}
+}
@@ -1,23 +1,27 @@
 package dm
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 )
 
 // ListObjects returns the bucket contains along with details on each item.
 func (a *OssAPI) ListObjects(bucketKey, limit, beginsWith, startAt string) (result BucketContent, err error) {
+	return a.ListObjectsWithContext(context.Background(), bucketKey, limit, beginsWith, startAt)
+}
+
+// ListObjectsWithContext is ListObjects with a caller-supplied context, so the request can be
+// cancelled or timed out by the caller.
+func (a *OssAPI) ListObjectsWithContext(ctx context.Context, bucketKey, limit, beginsWith, startAt string) (result BucketContent, err error) {
 
 	bearer, err := a.Authenticator.GetToken("data:read")
 	if err != nil {
 		return
 	}
 
-	result, err = listObjects(a.BaseUrl(), bucketKey, limit, beginsWith, startAt, bearer.AccessToken)
+	result, err = listObjects(ctx, defaultHTTPClient, a.BaseUrl(), bucketKey, limit, beginsWith, startAt, bearer.AccessToken)
 
 	return
 }
@@ -25,17 +29,14 @@ func (a *OssAPI) ListObjects(bucketKey, limit, beginsWith, startAt string) (resu
 // DownloadObject downloads an on object, given the URL-encoded object name.
 func (a *OssAPI) DownloadObject(bucketKey string, objectName string) (result []byte, err error) {
 
-	bearer, err := a.Authenticator.GetToken("data:read")
-	if err != nil {
-		return
-	}
+	buf := &memoryWriterAt{}
 
-	downloadUrl, err := getSignedDownloadUrl(a.BaseUrl(), bucketKey, objectName, bearer.AccessToken)
+	_, err = a.DownloadObjectTo(bucketKey, objectName, buf, DefaultDownloadOptions())
 	if err != nil {
 		return
 	}
 
-	result, err = downloadObjectUsingSignedUrl(&downloadUrl)
+	result = buf.data
 
 	return
 }
@@ -43,13 +44,35 @@ func (a *OssAPI) DownloadObject(bucketKey string, objectName string) (result []b
 // UploadObject adds to specified bucket the given data (can originate from a multipart-form or direct file read).
 // Return details on uploaded object, including the object URN (> ObjectId). Check uploadOkResult struct.
 func (a *OssAPI) UploadObject(bucketKey, objectName, fileToUpload string) (result UploadResult, err error) {
+	return a.UploadObjectWithOptions(bucketKey, objectName, fileToUpload, DefaultUploadOptions())
+}
+
+// UploadObjectWithOptions is UploadObject with control over the part size, parallelism and the
+// retry/backoff policy used for individual part uploads. See UploadOptions.
+func (a *OssAPI) UploadObjectWithOptions(bucketKey, objectName, fileToUpload string, opts UploadOptions) (result UploadResult, err error) {
 
-	job, err := newUploadJob(a, bucketKey, objectName, fileToUpload)
+	job, err := newUploadJob(a, bucketKey, objectName, fileToUpload, opts)
 	if err != nil {
 		return
 	}
 
-	result, err = job.uploadFile()
+	result, err = job.uploadFile(opts)
+
+	return
+}
+
+// UploadObjectFromReader uploads the content of r to the specified bucket and object name,
+// without requiring a filesystem path - useful for piping from an HTTP request body, a tar
+// stream, or an in-memory buffer. Pass size == -1 when the total length of r isn't known
+// upfront; parts are then counted as r is consumed instead of upfront from a file size.
+func (a *OssAPI) UploadObjectFromReader(bucketKey, objectName string, r io.Reader, size int64, opts UploadOptions) (result UploadResult, err error) {
+
+	job, err := newStreamingUploadJob(a, bucketKey, objectName, size, opts)
+	if err != nil {
+		return
+	}
+
+	result, err = job.uploadFromReader(r, size, opts)
 
 	return
 }
@@ -58,11 +81,9 @@ func (a *OssAPI) UploadObject(bucketKey, objectName, fileToUpload string) (resul
  *	SUPPORT FUNCTIONS
  */
 
-func listObjects(path, bucketKey, limit, beginsWith, startAt, token string) (result BucketContent, err error) {
-
-	task := http.Client{}
+func listObjects(ctx context.Context, doer HTTPDoer, path, bucketKey, limit, beginsWith, startAt, token string) (result BucketContent, err error) {
 
-	req, err := http.NewRequest("GET", path+"/"+bucketKey+"/objects", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", path+"/"+bucketKey+"/objects", nil)
 
 	if err != nil {
 		return
@@ -82,15 +103,14 @@ func listObjects(path, bucketKey, limit, beginsWith, startAt, token string) (res
 	req.URL.RawQuery = params.Encode()
 
 	req.Header.Set("Authorization", "Bearer "+token)
-	response, err := task.Do(req)
+	response, err := doer.Do(req)
 	if err != nil {
 		return
 	}
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		content, _ := io.ReadAll(response.Body)
-		err = errors.New("[" + strconv.Itoa(response.StatusCode) + "] " + string(content))
+		err = newAPIError(response)
 		return
 	}
 
@@ -111,16 +131,15 @@ type signedDownloadUrl struct {
 	Sha1 string `json:"sha1"`
 }
 
-func getSignedDownloadUrl(path, bucketKey, objectName string, token string) (result signedDownloadUrl, err error) {
+func getSignedDownloadUrl(ctx context.Context, doer HTTPDoer, path, bucketKey, objectName string, token string) (result signedDownloadUrl, err error) {
 
-	req, err := http.NewRequest("GET", path+"/"+bucketKey+"/objects/"+objectName+"/signeds3download", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", path+"/"+bucketKey+"/objects/"+objectName+"/signeds3download", nil)
 	if err != nil {
 		return
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	task := http.Client{}
-	response, err := task.Do(req)
+	response, err := doer.Do(req)
 	if err != nil {
 		return
 	}
@@ -129,41 +148,7 @@ func getSignedDownloadUrl(path, bucketKey, objectName string, token string) (res
 	if response.StatusCode == http.StatusOK {
 		err = json.NewDecoder(response.Body).Decode(&result)
 	} else {
-		content, _ := io.ReadAll(response.Body)
-		err = errors.New("[" + strconv.Itoa(response.StatusCode) + "] " + string(content))
-	}
-
-	return
-}
-
-func downloadObjectUsingSignedUrl(s *signedDownloadUrl) (result []byte, err error) {
-
-	req, err := http.NewRequest("GET", s.Url, nil)
-	if err != nil {
-		return
-	}
-
-	task := http.Client{}
-	response, err := task.Do(req)
-	if err != nil {
-		return
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		content, _ := io.ReadAll(response.Body)
-		err = errors.New("[" + strconv.Itoa(response.StatusCode) + "] " + string(content))
-		return
-	}
-
-	result, err = io.ReadAll(response.Body)
-	if err != nil {
-		return
-	}
-
-	receivedSize := len(result)
-	if receivedSize != s.Size {
-		err = fmt.Errorf("the file size doesn't match, expected %v, but received %v", s.Size, receivedSize)
+		err = newAPIError(response)
 	}
 
 	return
@@ -0,0 +1,222 @@
+package dm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// uploadStateSuffix is appended to the source file path to derive the default sidecar path
+// used to persist and resume upload state.
+const uploadStateSuffix = ".forgeupload"
+
+// uploadState is the on-disk representation of an in-progress multipart upload. It is
+// persisted as JSON after each successfully uploaded part, so the upload can be resumed - even
+// from a separate process - after a crash, via ResumeUpload.
+type uploadState struct {
+	BucketKey string `json:"bucketKey"`
+	ObjectKey string `json:"objectKey"`
+	UploadKey string `json:"uploadKey"`
+	FileSize  int64  `json:"fileSize"`
+	PartSize  int64  `json:"partSize"`
+	// CompletedParts maps a 1-based part number to the SHA-1 of the bytes uploaded for it.
+	CompletedParts map[int]string `json:"completedParts"`
+}
+
+// defaultStatePath returns the default sidecar path for a given source file.
+func defaultStatePath(fileToUpload string) string {
+	return fileToUpload + uploadStateSuffix
+}
+
+func loadUploadState(statePath string) (state uploadState, err error) {
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &state)
+	return
+}
+
+func saveUploadState(statePath string, state uploadState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath, data, 0o644)
+}
+
+func sha1Hex(data []byte) string {
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// uploadStateTracker persists upload progress to a JSON sidecar file after each completed part,
+// and lets the reader skip parts that were already uploaded in a previous attempt.
+type uploadStateTracker struct {
+	mu    sync.Mutex
+	path  string
+	state uploadState
+}
+
+func newUploadStateTracker(statePath, bucketKey, objectKey string, fileSize, partSize int64) *uploadStateTracker {
+	return &uploadStateTracker{
+		path: statePath,
+		state: uploadState{
+			BucketKey:      bucketKey,
+			ObjectKey:      objectKey,
+			FileSize:       fileSize,
+			PartSize:       partSize,
+			CompletedParts: map[int]string{},
+		},
+	}
+}
+
+// loadOrCreateTracker loads a tracker from statePath if a sidecar already exists there,
+// otherwise it starts a fresh one.
+func loadOrCreateTracker(statePath, bucketKey, objectKey string, fileSize, partSize int64) (*uploadStateTracker, error) {
+	state, err := loadUploadState(statePath)
+	if err == nil {
+		if state.CompletedParts == nil {
+			state.CompletedParts = map[int]string{}
+		}
+		return &uploadStateTracker{path: statePath, state: state}, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return newUploadStateTracker(statePath, bucketKey, objectKey, fileSize, partSize), nil
+}
+
+// setUploadKey, markPartDone, isDone and remove are all no-ops on a nil tracker, so callers that
+// don't support resumable state (streamed uploads from an io.Reader, which has no stable file
+// path to persist against) can simply pass a nil *uploadStateTracker.
+
+func (t *uploadStateTracker) setUploadKey(uploadKey string) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state.UploadKey == uploadKey {
+		return nil
+	}
+	t.state.UploadKey = uploadKey
+	return saveUploadState(t.path, t.state)
+}
+
+func (t *uploadStateTracker) markPartDone(partNumber int, data []byte) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state.CompletedParts[partNumber] = sha1Hex(data)
+	return saveUploadState(t.path, t.state)
+}
+
+// isDone reports whether partNumber was already uploaded and its recorded SHA-1 matches data.
+func (t *uploadStateTracker) isDone(partNumber int, data []byte) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sum, ok := t.state.CompletedParts[partNumber]
+	return ok && sum == sha1Hex(data)
+}
+
+// hasCompleted reports whether partNumber has a recorded completion at all, without the data to
+// verify its checksum against. Used to decide whether a signed URL is worth requesting for a
+// part before the corresponding file bytes have even been read; isDone is still the source of
+// truth for whether the part is actually skipped once its data is in hand.
+func (t *uploadStateTracker) hasCompleted(partNumber int) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.state.CompletedParts[partNumber]
+	return ok
+}
+
+// remove deletes the sidecar file; called once the upload has completed successfully.
+func (t *uploadStateTracker) remove() {
+	if t == nil {
+		return
+	}
+	_ = os.Remove(t.path)
+}
+
+// ResumeUpload resumes a multipart upload previously started via UploadObjectWithOptions, using
+// the progress persisted at statePath (defaultStatePath(fileToUpload) when empty). Signed URLs
+// are only requested for the parts that are still missing, since Autodesk upload URLs expire in
+// at most 60 minutes and the URLs from the original attempt can no longer be relied upon; parts
+// whose recomputed SHA-1 matches the recorded one are skipped without re-uploading.
+func (a *OssAPI) ResumeUpload(fileToUpload, statePath string, opts UploadOptions) (result UploadResult, err error) {
+
+	if statePath == "" {
+		statePath = defaultStatePath(fileToUpload)
+	}
+
+	state, err := loadUploadState(statePath)
+	if err != nil {
+		err = fmt.Errorf("error loading upload state from %v:\n%w", statePath, err)
+		return
+	}
+
+	opts.PartSize = state.PartSize
+	opts.StatePath = statePath
+
+	job, err := newUploadJob(a, state.BucketKey, state.ObjectKey, fileToUpload, opts)
+	if err != nil {
+		return
+	}
+	job.uploadKey = state.UploadKey
+
+	result, err = job.uploadFile(opts)
+
+	return
+}
+
+// AbortUpload cancels an in-progress multipart upload, instructing OSS to discard any parts
+// already uploaded to S3 for uploadKey. Call this once ResumeUpload is no longer viable, for
+// example because the 24-hour window to complete the upload has passed.
+func (a *OssAPI) AbortUpload(bucketKey, objectKey, uploadKey string) (err error) {
+
+	bearer, err := a.Authenticator.GetToken("data:write data:read")
+	if err != nil {
+		return
+	}
+
+	bodyJson, err := json.Marshal(struct {
+		UploadKey string `json:"uploadKey"`
+	}{UploadKey: uploadKey})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), "DELETE", a.BaseUrl()+"/"+bucketKey+"/objects/"+objectKey+"/"+signedS3UploadEndpoint, bytes.NewBuffer(bodyJson))
+	if err != nil {
+		return
+	}
+	addOrSetHeader(req, "Authorization", "Bearer "+bearer.AccessToken)
+	addOrSetHeader(req, "Content-Type", "application/json")
+
+	response, err := defaultHTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNoContent {
+		err = newAPIError(response)
+	}
+
+	return
+}
@@ -0,0 +1,125 @@
+package dm
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestResumeUpload_SkipsCompletedParts seeds a .forgeupload sidecar recording part 1 as already
+// uploaded, with the real SHA-1 of its bytes, then resumes the upload: part 1 must not be
+// re-requested or re-uploaded, and only part 2 should hit the signed-URL and PUT endpoints.
+func TestResumeUpload_SkipsCompletedParts(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "source.bin")
+	part1 := []byte("0123456789")
+	part2 := []byte("abcde")
+	data := append(append([]byte{}, part1...), part2...)
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		t.Fatalf("writing source file: %v", err)
+	}
+
+	statePath := filePath + uploadStateSuffix
+	state := uploadState{
+		BucketKey:      "bucket",
+		ObjectKey:      "obj",
+		UploadKey:      "upload-key",
+		FileSize:       int64(len(data)),
+		PartSize:       10,
+		CompletedParts: map[int]string{1: sha1Hex(part1)},
+	}
+	if err := saveUploadState(statePath, state); err != nil {
+		t.Fatalf("saveUploadState: %v", err)
+	}
+
+	var signedURLRequests []string
+	var part1Requested, part2Uploaded bool
+
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/buckets/bucket/objects/obj/signeds3upload", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			signedURLRequests = append(signedURLRequests, r.URL.Query().Get("firstPart")+"-"+r.URL.Query().Get("parts"))
+			first := r.URL.Query().Get("firstPart")
+			writeJSON(t, w, signedUploadUrls{UploadKey: "upload-key", Urls: []string{srv.URL + "/part/" + first}})
+		case http.MethodPost:
+			writeJSON(t, w, UploadResult{})
+		}
+	})
+	mux.HandleFunc("/part/1", func(w http.ResponseWriter, r *http.Request) {
+		part1Requested = true
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/part/2", func(w http.ResponseWriter, r *http.Request) {
+		part2Uploaded = true
+		w.WriteHeader(http.StatusOK)
+	})
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	a := &OssAPI{BucketAPI: testBucketAPI(srv)}
+
+	opts := UploadOptions{Concurrency: 1, MaxRetries: 3, RetryBackoff: time.Millisecond}
+	if _, err := a.ResumeUpload(filePath, statePath, opts); err != nil {
+		t.Fatalf("ResumeUpload: %v", err)
+	}
+
+	if part1Requested {
+		t.Errorf("part 1 was re-uploaded, but its sidecar checksum already matched")
+	}
+	if !part2Uploaded {
+		t.Errorf("part 2 was never uploaded")
+	}
+	for _, req := range signedURLRequests {
+		if req == "1-1" || req == "1-2" {
+			t.Errorf("a signed URL was requested covering the already-completed part 1: %v", signedURLRequests)
+		}
+	}
+}
+
+// TestAbortUpload_SendsDeleteAndSurfacesAPIError checks the abort request shape and that a
+// non-2xx response is surfaced as an *APIError rather than swallowed.
+func TestAbortUpload_SendsDeleteAndSurfacesAPIError(t *testing.T) {
+	var gotMethod string
+	var gotBody struct {
+		UploadKey string `json:"uploadKey"`
+	}
+	status := http.StatusNoContent
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/buckets/bucket/objects/obj/signeds3upload", func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(status)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	a := &OssAPI{BucketAPI: testBucketAPI(srv)}
+
+	if err := a.AbortUpload("bucket", "obj", "upload-key"); err != nil {
+		t.Fatalf("AbortUpload: %v", err)
+	}
+	if gotMethod != http.MethodDelete {
+		t.Errorf("method = %v, want DELETE", gotMethod)
+	}
+	if gotBody.UploadKey != "upload-key" {
+		t.Errorf("uploadKey in request body = %q, want %q", gotBody.UploadKey, "upload-key")
+	}
+
+	status = http.StatusInternalServerError
+	err := a.AbortUpload("bucket", "obj", "upload-key")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("AbortUpload error = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("APIError.StatusCode = %v, want %v", apiErr.StatusCode, http.StatusInternalServerError)
+	}
+}
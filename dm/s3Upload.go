@@ -29,15 +29,17 @@ package dm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"math/rand"
 	"net/http"
 	"os"
 	"path"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/thedevsaddam/retry"
@@ -60,7 +62,56 @@ var (
 	defaultSize = int64(100 * megaByte)
 )
 
-func newUploadJob(api BucketAPI, bucketKey, objectName, fileToUpload string) (job uploadJob, err error) {
+// UploadOptions configures how UploadObjectWithOptions splits, parallelizes and retries the
+// parts of a multipart upload.
+type UploadOptions struct {
+	// Context, if set, is attached to every part upload so the caller can cancel or time out
+	// the whole operation; the first non-retriable part failure also cancels every in-flight
+	// and queued part. Defaults to context.Background().
+	Context context.Context
+	// PartSize is the size, in bytes, of each uploaded part. Defaults to defaultSize (100MB).
+	PartSize int64
+	// Concurrency is the number of parts uploaded in parallel. Defaults to 1 (serial).
+	Concurrency int
+	// MaxRetries is the number of attempts made per part before giving up. Defaults to 3.
+	MaxRetries int
+	// RetryBackoff is the base delay used for exponential backoff between retries; the actual
+	// delay is RetryBackoff*2^attempt, capped at maxBackoff, plus jitter. Defaults to 1 second.
+	RetryBackoff time.Duration
+	// StatePath, when set, persists upload progress to a JSON sidecar file after each part so the
+	// upload can later be resumed via ResumeUpload; pass defaultStatePath(fileToUpload) to use
+	// the conventional ".forgeupload" location. Left empty (the default), no sidecar file is
+	// written and the upload cannot be resumed - matching the behaviour of this package before
+	// resumable uploads existed. A failure to persist progress is never fatal: it's ignored and
+	// the upload proceeds, just without the ability to resume from that point.
+	StatePath string
+	// HTTPClient is used to issue every HTTP request of the upload. Defaults to a shared
+	// http.Client with sane timeouts and connection pooling; set it to plug in an instrumented
+	// or mocked client.
+	HTTPClient HTTPDoer
+}
+
+// DefaultUploadOptions returns the UploadOptions used by UploadObject: a serial upload with a
+// conservative retry policy, matching the previous behaviour of this package.
+func DefaultUploadOptions() UploadOptions {
+	return UploadOptions{
+		Context:      context.Background(),
+		PartSize:     defaultSize,
+		Concurrency:  1,
+		MaxRetries:   3,
+		RetryBackoff: time.Second,
+	}
+}
+
+// partJob is a single part to be uploaded: its 1-based part number, its payload, and the
+// signed URL it should be PUT to.
+type partJob struct {
+	partNumber int
+	data       []byte
+	url        string
+}
+
+func newUploadJob(api BucketAPI, bucketKey, objectName, fileToUpload string, opts UploadOptions) (job uploadJob, err error) {
 
 	job = uploadJob{}
 	job.api = api
@@ -75,19 +126,90 @@ func newUploadJob(api BucketAPI, bucketKey, objectName, fileToUpload string) (jo
 		return
 	}
 
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = defaultSize
+	}
+
 	// Determine the required number of parts
 	// - In the examples, typically a chunk size of 5 or 10 MB is used.
 	// - In the old API, the boundary for multipart uploads was 100 MB.
 	//   => See const defaultSize
 
 	job.fileSize = fileInfo.Size()
-	job.totalParts = int((job.fileSize / defaultSize) + 1)
+	job.totalParts = int((job.fileSize / partSize) + 1)
 	job.numberOfBatches = (job.totalParts / maxParts) + 1
 
 	return
 }
 
-func (job *uploadJob) uploadFile() (result UploadResult, err error) {
+// newStreamingUploadJob builds an uploadJob for UploadObjectFromReader. When size is known
+// (size >= 0) the part count is computed upfront exactly as newUploadJob does; when size is
+// unknown (size == -1) job.totalParts and job.numberOfBatches are left at zero, since parts are
+// instead counted as uploadFromReader drains the reader.
+func newStreamingUploadJob(api BucketAPI, bucketKey, objectName string, size int64, opts UploadOptions) (job uploadJob, err error) {
+
+	job = uploadJob{}
+	job.api = api
+	job.bucketKey = bucketKey
+	job.objectKey = objectName
+	job.minutesExpiration = minutesExpiration
+	job.uploadKey = ""
+
+	if size < 0 {
+		return job, nil
+	}
+
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = defaultSize
+	}
+
+	job.fileSize = size
+	job.totalParts = int((size / partSize) + 1)
+	job.numberOfBatches = (job.totalParts / maxParts) + 1
+
+	return
+}
+
+// uploadFile uploads the source file to OSS per opts, dispatching the parts of each
+// signeds3upload batch through a worker pool bounded by opts.Concurrency.
+func (job *uploadJob) uploadFile(opts UploadOptions) (result UploadResult, err error) {
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = defaultSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	maxRetries := opts.MaxRetries
+	if maxRetries < 1 {
+		maxRetries = 3
+	}
+	backoff := opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	doer := httpDoerOrDefault(opts.HTTPClient)
+
+	// tracker is nil, and therefore a no-op, unless the caller explicitly opted into resumable
+	// state via opts.StatePath - see the field doc on UploadOptions.StatePath.
+	var tracker *uploadStateTracker
+	if opts.StatePath != "" {
+		tracker, err = loadOrCreateTracker(opts.StatePath, job.bucketKey, job.objectKey, job.fileSize, partSize)
+		if err != nil {
+			return result, fmt.Errorf("error loading upload state from %v:\n%w", opts.StatePath, err)
+		}
+		if job.uploadKey != "" {
+			_ = tracker.setUploadKey(job.uploadKey)
+		}
+	}
 
 	file, err := os.Open(job.fileToUpload)
 	if err != nil {
@@ -95,6 +217,9 @@ func (job *uploadJob) uploadFile() (result UploadResult, err error) {
 	}
 	defer file.Close()
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	partsCounter := 0
 	for i := 0; i < job.numberOfBatches; i++ {
 
@@ -102,52 +227,115 @@ func (job *uploadJob) uploadFile() (result UploadResult, err error) {
 
 		parts := job.getParts(partsCounter)
 
-		// generate signed S3 upload url(s)
-		tmpResult, err := retry.Do(3, 3*time.Second, job.getSignedUploadUrls, firstPart, parts)
+		// generate signed S3 upload url(s), skipping parts tracker already recorded as done on
+		// a previous attempt
+		uploadUrls, err := job.signedUploadUrlsForBatch(ctx, doer, tracker, firstPart, parts)
 		if err != nil {
-			err = fmt.Errorf("Error getting signed URLs for parts %v-%v :\n%w", firstPart, parts, err)
-			return
+			return result, err
 		}
-		uploadUrls, _ := tmpResult[0].(signedUploadUrls)
 
-		if i == 0 {
+		if job.uploadKey == "" {
 			// remember the uploadKey when requesting signed URLs for the first time
 			job.uploadKey = uploadUrls.UploadKey
+			_ = tracker.setUploadKey(job.uploadKey)
 		}
 
-		// upload the file in chunks to the signed url(s)
-		for _, url := range uploadUrls.Urls {
+		// upload this batch's parts through a bounded worker pool
+		if _, _, err = job.uploadBatch(ctx, doer, file, uploadUrls.Urls, firstPart, partSize, concurrency, maxRetries, backoff, tracker); err != nil {
+			return result, err
+		}
 
-			// read a chunk of the file
-			bytesSlice := make([]byte, defaultSize)
+		partsCounter += maxParts
+	}
 
-			bytesRead, err := file.Read(bytesSlice)
-			if err != nil {
-				if err != io.EOF {
-					err = fmt.Errorf("Error reading the file to upload:\n%w", err)
-					return
-				}
-				// EOF reached
-			}
+	// complete the upload
+	tmpResult, err := retry.Do(3, 3*time.Second, func() (UploadResult, error) { return job.completeUpload(ctx, doer, job.fileSize) })
+	if err != nil {
+		err = fmt.Errorf("error completing the upload:\n%w", err)
+		return
+	}
+	result, _ = tmpResult[0].(UploadResult)
 
-			// upload the chunk to the signed URL
-			if bytesRead > 0 {
-				buffer := bytes.NewBuffer(bytesSlice[:bytesRead])
-				_, err = retry.Do(3, 3*time.Second, uploadChunk, url, buffer)
-				if err != nil {
-					err = fmt.Errorf("Error uploading a chunk to URL:\n- %v\n%w", url, err)
-					return
-				}
+	tracker.remove()
+
+	return
+}
+
+// uploadFromReader drives UploadObjectFromReader: batches of up to maxParts signed URLs are
+// requested as r is consumed, using the uploadKey retained from the first batch, so the total
+// part count never needs to be known in advance. When size is known the final batch is trimmed
+// to the remaining parts, exactly as getParts does for file-based uploads; completeUpload is
+// told the number of bytes actually read rather than a pre-computed size.
+func (job *uploadJob) uploadFromReader(r io.Reader, size int64, opts UploadOptions) (result UploadResult, err error) {
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = defaultSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	maxRetries := opts.MaxRetries
+	if maxRetries < 1 {
+		maxRetries = 3
+	}
+	backoff := opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	doer := httpDoerOrDefault(opts.HTTPClient)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var uploaded int64
+	nextPart := 1
+
+	for {
+		parts := maxParts
+		if size >= 0 {
+			remaining := job.totalParts - (nextPart - 1)
+			if remaining <= 0 {
+				break
+			}
+			if remaining < parts {
+				parts = remaining
 			}
 		}
 
-		partsCounter += maxParts
+		// streamed uploads aren't resumable (there is no seekable source to skip ahead in), so
+		// no uploadStateTracker is passed here - every part in the batch is requested
+		uploadUrls, err := job.signedUploadUrlsForBatch(ctx, doer, nil, nextPart, parts)
+		if err != nil {
+			return result, err
+		}
+
+		if job.uploadKey == "" {
+			// remember the uploadKey when requesting signed URLs for the first time
+			job.uploadKey = uploadUrls.UploadKey
+		}
+		partsUsed, n, batchErr := job.uploadBatch(ctx, doer, r, uploadUrls.Urls, nextPart, partSize, concurrency, maxRetries, backoff, nil)
+		uploaded += n
+		nextPart += partsUsed
+		if batchErr != nil {
+			return result, batchErr
+		}
+		if partsUsed < len(uploadUrls.Urls) {
+			// r was exhausted before this batch's URLs were
+			break
+		}
 	}
 
 	// complete the upload
-	tmpResult, err := retry.Do(3, 3*time.Second, job.completeUpload)
+	tmpResult, err := retry.Do(3, 3*time.Second, func() (UploadResult, error) { return job.completeUpload(ctx, doer, uploaded) })
 	if err != nil {
-		err = fmt.Errorf("Error completing the upload:\n%w", err)
+		err = fmt.Errorf("error completing the upload:\n%w", err)
 		return
 	}
 	result, _ = tmpResult[0].(UploadResult)
@@ -155,6 +343,166 @@ func (job *uploadJob) uploadFile() (result UploadResult, err error) {
 	return
 }
 
+// uploadBatch reads r sequentially and hands out (partNumber, []byte) jobs to a pool of
+// concurrency workers over a channel sized to concurrency. At most 2*concurrency+1 parts are held
+// at once (one per worker, up to concurrency queued in the channel, and the one being read), so
+// memory usage stays proportional to concurrency*partSize regardless of how much data there is
+// to upload. The first non-retriable
+// part failure cancels ctx, which stops the remaining in-flight and queued uploads. It returns
+// how many of urls were actually used and how many bytes were read, so a caller streaming from
+// a reader of unknown length can tell that r was exhausted before the batch was.
+func (job *uploadJob) uploadBatch(ctx context.Context, doer HTTPDoer, r io.Reader, urls []string, firstPart int, partSize int64, concurrency, maxRetries int, backoff time.Duration, tracker *uploadStateTracker) (partsUsed int, bytesRead int64, err error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan partJob, concurrency)
+	errs := make(chan error, 1)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pj := range jobs {
+				if err := job.uploadPartWithRetry(ctx, doer, pj, maxRetries, backoff); err != nil {
+					select {
+					case errs <- err:
+						cancel()
+					default:
+					}
+					return
+				}
+				// A sidecar write failure doesn't affect the part that just succeeded against
+				// S3, so it's ignored rather than aborting the upload - it only costs the
+				// ability to skip this part if the upload is later resumed.
+				_ = tracker.markPartDone(pj.partNumber, pj.data)
+			}
+		}()
+	}
+
+	var readErr error
+readLoop:
+	for i, url := range urls {
+		partNumber := firstPart + i
+		buf := make([]byte, partSize)
+		n, rerr := io.ReadFull(r, buf)
+		if n > 0 {
+			partsUsed++
+			bytesRead += int64(n)
+			if !tracker.isDone(partNumber, buf[:n]) {
+				if url == "" {
+					// signedUploadUrlsForBatch skipped this part number on the assumption that
+					// tracker's recorded completion meant it didn't need a URL; that recorded
+					// completion just turned out not to match the actual bytes read (e.g. the
+					// source file changed since the previous attempt), so fetch one now.
+					fresh, ferr := job.getSignedUploadUrls(ctx, doer, partNumber, 1)
+					if ferr != nil {
+						readErr = fmt.Errorf("error getting signed URL for part %v:\n%w", partNumber, ferr)
+						break readLoop
+					}
+					if len(fresh.Urls) == 0 {
+						readErr = fmt.Errorf("no signed URL returned for part %v", partNumber)
+						break readLoop
+					}
+					url = fresh.Urls[0]
+				}
+				select {
+				case jobs <- partJob{partNumber: partNumber, data: buf[:n], url: url}:
+				case <-ctx.Done():
+					break readLoop
+				}
+			}
+		}
+		if rerr != nil {
+			if rerr != io.EOF && rerr != io.ErrUnexpectedEOF {
+				readErr = fmt.Errorf("error reading the data to upload:\n%w", rerr)
+			}
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	select {
+	case err = <-errs:
+		return partsUsed, bytesRead, err
+	default:
+	}
+
+	return partsUsed, bytesRead, readErr
+}
+
+// uploadPartWithRetry uploads a single part, retrying with exponential backoff and jitter on
+// retriable failures (100-199, 429, 500-599) and requesting a fresh signed URL for the part on
+// a 403 (the signed URL has expired).
+func (job *uploadJob) uploadPartWithRetry(ctx context.Context, doer HTTPDoer, pj partJob, maxRetries int, backoff time.Duration) error {
+
+	url := pj.url
+
+	var lastErr error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		err := uploadChunkWithContext(ctx, doer, url, bytes.NewBuffer(pj.data))
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			if apiErr.IsExpiredURL() {
+				fresh, rerr := job.getSignedUploadUrls(ctx, doer, pj.partNumber, 1)
+				if rerr != nil {
+					return fmt.Errorf("error refreshing expired signed URL for part %v:\n%w", pj.partNumber, rerr)
+				}
+				if len(fresh.Urls) == 0 {
+					return fmt.Errorf("no signed URL returned when refreshing part %v", pj.partNumber)
+				}
+				url = fresh.Urls[0]
+				continue
+			}
+			if !apiErr.IsRetryable() {
+				return lastErr
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoffWithJitter(backoff, attempt)):
+		}
+	}
+
+	return fmt.Errorf("part %v failed after %v attempts:\n%w", pj.partNumber, maxRetries, lastErr)
+}
+
+// maxBackoff caps the exponential part of the delay backoffWithJitter returns; the jitter, up to
+// base, is added on top of the capped value. Without a cap, a caller-set high
+// MaxRetries would eventually make base<<attempt overflow int64 into a negative duration, so
+// time.After would fire immediately and the "exponential backoff" would collapse into a hot
+// retry loop.
+const maxBackoff = 2 * time.Minute
+
+// backoffWithJitter returns base*2^attempt plus a random jitter in [0, base), capped at
+// maxBackoff so attempt can grow arbitrarily large without overflowing.
+func backoffWithJitter(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 0; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay + time.Duration(rand.Int63n(int64(base)))
+}
+
 // getParts gets the number of parts that must be processed in this batch.
 func (job *uploadJob) getParts(partsCounter int) int {
 
@@ -171,8 +519,48 @@ func (job *uploadJob) getParts(partsCounter int) int {
 	return parts
 }
 
+// signedUploadUrlsForBatch returns one signed URL per part in [firstPart, firstPart+parts-1],
+// skipping the call to getSignedUploadUrls for any part tracker already recorded as completed in
+// a previous attempt - resuming an upload must not burn a fresh, time-limited signed URL on a
+// part that doesn't need to be re-uploaded. Skipped parts are left with an empty URL in the
+// result; uploadBatch only relies on it once it has re-verified tracker.isDone against the
+// actual bytes read, and falls back to a single-part fetch if that check no longer matches (see
+// uploadBatch). When tracker is nil (or nothing in the range is done yet) this reduces to the one
+// getSignedUploadUrls call across the whole range that both callers used to make directly.
+func (job *uploadJob) signedUploadUrlsForBatch(ctx context.Context, doer HTTPDoer, tracker *uploadStateTracker, firstPart, parts int) (result signedUploadUrls, err error) {
+
+	result.Urls = make([]string, parts)
+
+	for start := firstPart; start < firstPart+parts; {
+		if tracker.hasCompleted(start) {
+			start++
+			continue
+		}
+		end := start + 1
+		for end < firstPart+parts && !tracker.hasCompleted(end) {
+			end++
+		}
+
+		tmpResult, rerr := retry.Do(3, 3*time.Second, func() (signedUploadUrls, error) {
+			return job.getSignedUploadUrls(ctx, doer, start, end-start)
+		})
+		if rerr != nil {
+			return result, fmt.Errorf("error getting signed URLs for parts %v-%v :\n%w", start, end-1, rerr)
+		}
+		batch, _ := tmpResult[0].(signedUploadUrls)
+		if result.UploadKey == "" {
+			result.UploadKey = batch.UploadKey
+		}
+		copy(result.Urls[start-firstPart:], batch.Urls)
+
+		start = end
+	}
+
+	return result, nil
+}
+
 // getSignedUploadUrls calls the signedS3UploadEndpoint
-func (job *uploadJob) getSignedUploadUrls(firstPart, parts int) (result signedUploadUrls, err error) {
+func (job *uploadJob) getSignedUploadUrls(ctx context.Context, doer HTTPDoer, firstPart, parts int) (result signedUploadUrls, err error) {
 
 	// - https://forge.autodesk.com/en/docs/data/v2/reference/http/buckets-:bucketKey-objects-:objectKey-signeds3upload-GET/
 
@@ -185,7 +573,7 @@ func (job *uploadJob) getSignedUploadUrls(firstPart, parts int) (result signedUp
 	}
 
 	// request the signed urls
-	req, err := http.NewRequest("GET", job.getSignedS3UploadPath(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", job.getSignedS3UploadPath(), nil)
 	if err != nil {
 		return
 	}
@@ -203,16 +591,14 @@ func (job *uploadJob) getSignedUploadUrls(firstPart, parts int) (result signedUp
 	// assign encoded query string to http request
 	req.URL.RawQuery = q.Encode()
 
-	task := http.Client{}
-	response, err := task.Do(req)
+	response, err := doer.Do(req)
 	if err != nil {
 		return
 	}
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		content, _ := ioutil.ReadAll(response.Body)
-		err = errors.New("[" + strconv.Itoa(response.StatusCode) + "] " + string(content))
+		err = newAPIError(response)
 		return
 	}
 
@@ -221,10 +607,11 @@ func (job *uploadJob) getSignedUploadUrls(firstPart, parts int) (result signedUp
 	return
 }
 
-// uploadChunk uploads a chunk of bytes to a given signedUrl.
-func uploadChunk(signedUrl string, buffer *bytes.Buffer) (err error) {
+// uploadChunkWithContext PUTs a chunk of bytes to a signed URL, returning an *APIError on a
+// non-2xx response so callers can decide whether to retry or refresh the URL.
+func uploadChunkWithContext(ctx context.Context, doer HTTPDoer, signedUrl string, buffer *bytes.Buffer) (err error) {
 
-	req, err := http.NewRequest("PUT", signedUrl, buffer)
+	req, err := http.NewRequestWithContext(ctx, "PUT", signedUrl, buffer)
 	if err != nil {
 		return
 	}
@@ -234,8 +621,7 @@ func uploadChunk(signedUrl string, buffer *bytes.Buffer) (err error) {
 	addOrSetHeader(req, "Content-Type", "application/octet-stream")
 	addOrSetHeader(req, "Content-Length", strconv.Itoa(l))
 
-	task := http.Client{}
-	response, err := task.Do(req)
+	response, err := doer.Do(req)
 	if err != nil {
 		return
 	}
@@ -245,8 +631,7 @@ func uploadChunk(signedUrl string, buffer *bytes.Buffer) (err error) {
 		return
 	}
 
-	content, _ := ioutil.ReadAll(response.Body)
-	err = errors.New("[" + strconv.Itoa(response.StatusCode) + "] " + string(content))
+	err = newAPIError(response)
 
 	return
 }
@@ -254,7 +639,10 @@ func uploadChunk(signedUrl string, buffer *bytes.Buffer) (err error) {
 // completeUpload instructs OSS to complete the object creation process after the bytes have been uploaded directly to S3.
 // An object will not be accessible until this endpoint is called.
 // This endpoint must be called within 24 hours of the upload beginning, otherwise the object will be discarded, and the upload must begin again from scratch.
-func (job *uploadJob) completeUpload() (result UploadResult, err error) {
+// size is the number of bytes actually uploaded: for file-based uploads this is job.fileSize,
+// but UploadObjectFromReader passes the count it accumulated while draining the reader, since
+// job.fileSize isn't known upfront when the source size is unknown.
+func (job *uploadJob) completeUpload(ctx context.Context, doer HTTPDoer, size int64) (result UploadResult, err error) {
 
 	// - https://forge.autodesk.com/en/docs/data/v2/reference/http/buckets-:bucketKey-objects-:objectKey-signeds3upload-POST/
 
@@ -273,7 +661,7 @@ func (job *uploadJob) completeUpload() (result UploadResult, err error) {
 		Size      int    `json:"size"`
 	}{
 		UploadKey: job.uploadKey,
-		Size:      int(job.fileSize),
+		Size:      int(size),
 	}
 
 	bodyJson, err := json.Marshal(bodyData)
@@ -281,7 +669,7 @@ func (job *uploadJob) completeUpload() (result UploadResult, err error) {
 		return
 	}
 
-	req, err := http.NewRequest("POST", job.getSignedS3UploadPath(), bytes.NewBuffer(bodyJson))
+	req, err := http.NewRequestWithContext(ctx, "POST", job.getSignedS3UploadPath(), bytes.NewBuffer(bodyJson))
 	if err != nil {
 		return
 	}
@@ -290,16 +678,14 @@ func (job *uploadJob) completeUpload() (result UploadResult, err error) {
 	addOrSetHeader(req, "Content-Type", "application/json")
 	addOrSetHeader(req, "x-ads-meta-Content-Type", "application/octet-stream")
 
-	task := http.Client{}
-	response, err := task.Do(req)
+	response, err := doer.Do(req)
 	if err != nil {
 		return
 	}
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		content, _ := ioutil.ReadAll(response.Body)
-		err = errors.New("[" + strconv.Itoa(response.StatusCode) + "] " + string(content))
+		err = newAPIError(response)
 		return
 	}
 
@@ -0,0 +1,197 @@
+package dm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeAuthenticator satisfies the Authenticator interface against an httptest.Server, so the
+// upload/download code paths can be exercised without a real Forge account.
+type fakeAuthenticator struct {
+	hostPath string
+}
+
+func (f fakeAuthenticator) GetToken(scope string) (Bearer, error) {
+	return Bearer{AccessToken: "test-token"}, nil
+}
+
+func (f fakeAuthenticator) GetHostPath() string {
+	return f.hostPath
+}
+
+func testBucketAPI(srv *httptest.Server) BucketAPI {
+	return BucketAPI{
+		Authenticator: fakeAuthenticator{hostPath: srv.URL},
+		BucketAPIPath: "/buckets",
+	}
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Fatalf("encoding response: %v", err)
+	}
+}
+
+type attemptCounter struct {
+	mu    sync.Mutex
+	count map[int]int
+}
+
+func newAttemptCounter() *attemptCounter {
+	return &attemptCounter{count: map[int]int{}}
+}
+
+func (c *attemptCounter) inc(part int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count[part]++
+	return c.count[part]
+}
+
+// TestUploadFile_ConcurrentPartsAndRetry drives a 3-part upload through a worker pool with
+// concurrency 2. The first PUT of part 2 fails with a 500, which must be retried and eventually
+// succeed, while the other parts upload successfully alongside it.
+func TestUploadFile_ConcurrentPartsAndRetry(t *testing.T) {
+	dir := t.TempDir()
+	filePath := dir + "/source.bin"
+	data := make([]byte, 29) // 3 parts of 10, 10 and 9 bytes at partSize 10
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		t.Fatalf("writing source file: %v", err)
+	}
+
+	attempts := newAttemptCounter()
+	var completeCalled int32
+
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/buckets/bucket/objects/obj/signeds3upload", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			var first, n int
+			fmt.Sscanf(r.URL.Query().Get("firstPart"), "%d", &first)
+			fmt.Sscanf(r.URL.Query().Get("parts"), "%d", &n)
+			urls := make([]string, n)
+			for i := 0; i < n; i++ {
+				urls[i] = fmt.Sprintf("%s/part/%d", srv.URL, first+i)
+			}
+			writeJSON(t, w, signedUploadUrls{UploadKey: "upload-key", Urls: urls})
+		case http.MethodPost:
+			atomic.AddInt32(&completeCalled, 1)
+			writeJSON(t, w, UploadResult{})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/part/", func(w http.ResponseWriter, r *http.Request) {
+		var partNumber int
+		fmt.Sscanf(r.URL.Path, "/part/%d", &partNumber)
+		attempt := attempts.inc(partNumber)
+		if partNumber == 2 && attempt == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	job, err := newUploadJob(testBucketAPI(srv), "bucket", "obj", filePath, UploadOptions{PartSize: 10})
+	if err != nil {
+		t.Fatalf("newUploadJob: %v", err)
+	}
+
+	opts := UploadOptions{
+		PartSize:     10,
+		Concurrency:  2,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	}
+	if _, err := job.uploadFile(opts); err != nil {
+		t.Fatalf("uploadFile: %v", err)
+	}
+
+	if got := attempts.inc(2) - 1; got != 2 {
+		t.Errorf("part 2 attempts = %v, want 2 (one failure, one retry)", got)
+	}
+	if atomic.LoadInt32(&completeCalled) != 1 {
+		t.Errorf("completeUpload called %v times, want 1", completeCalled)
+	}
+}
+
+// TestUploadFile_RefreshesExpiredSignedURL verifies that a 403 on a part PUT triggers a
+// single-part getSignedUploadUrls refresh rather than a blind retry against the stale URL.
+func TestUploadFile_RefreshesExpiredSignedURL(t *testing.T) {
+	dir := t.TempDir()
+	filePath := dir + "/source.bin"
+	if err := os.WriteFile(filePath, []byte("hello"), 0o644); err != nil {
+		t.Fatalf("writing source file: %v", err)
+	}
+
+	var signedURLRequests int32
+	var staleURLHit int32
+
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/buckets/bucket/objects/obj/signeds3upload", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			n := atomic.AddInt32(&signedURLRequests, 1)
+			writeJSON(t, w, signedUploadUrls{UploadKey: "upload-key", Urls: []string{fmt.Sprintf("%s/part/%d", srv.URL, n)}})
+		case http.MethodPost:
+			writeJSON(t, w, UploadResult{})
+		}
+	})
+	mux.HandleFunc("/part/1", func(w http.ResponseWriter, r *http.Request) {
+		atomic.StoreInt32(&staleURLHit, 1)
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc("/part/2", func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+	srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	job, err := newUploadJob(testBucketAPI(srv), "bucket", "obj", filePath, UploadOptions{PartSize: 10})
+	if err != nil {
+		t.Fatalf("newUploadJob: %v", err)
+	}
+
+	opts := UploadOptions{PartSize: 10, Concurrency: 1, MaxRetries: 3, RetryBackoff: time.Millisecond}
+	if _, err := job.uploadFile(opts); err != nil {
+		t.Fatalf("uploadFile: %v", err)
+	}
+
+	if atomic.LoadInt32(&staleURLHit) != 1 {
+		t.Errorf("expected the stale signed URL to be hit once before being refreshed")
+	}
+	if got := atomic.LoadInt32(&signedURLRequests); got != 2 {
+		t.Errorf("signed URL requests = %v, want 2 (initial batch + refresh for the expired part)", got)
+	}
+}
+
+// TestBackoffWithJitter_CapsDelay ensures a high attempt count can't overflow base<<attempt into
+// a negative duration.
+func TestBackoffWithJitter_CapsDelay(t *testing.T) {
+	delay := backoffWithJitter(time.Second, 40)
+	if delay < 0 {
+		t.Fatalf("backoffWithJitter returned a negative duration: %v", delay)
+	}
+	if delay > maxBackoff+time.Second {
+		t.Errorf("backoffWithJitter = %v, want capped around maxBackoff (%v)", delay, maxBackoff)
+	}
+}